@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	telegramMessageLimit = 4096
+	telegramCaptionLimit = 1024
+)
+
+// parseHtmlContent converts article HTML into Telegram-safe HTML: <a href> becomes
+// an inline link, <strong>/<em> become bold/italic, and <li> become bulleted lines.
+// Everything else is flattened to escaped plain text.
+func parseHtmlContent(htmlContent string) string {
+	reader := strings.NewReader(htmlContent)
+	doc, err := goquery.NewDocumentFromReader(reader)
+	if err != nil {
+		// If parsing fails, return the original content escaped as plain text
+		return escapeTelegramHTML(htmlContent)
+	}
+
+	var result strings.Builder
+
+	// Process paragraphs and lists
+	doc.Find("p, ul, li").Each(func(i int, s *goquery.Selection) {
+		tagName := goquery.NodeName(s)
+
+		text := formatInlineHTML(s)
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+
+		// Replace Georgian Lari icon with the proper symbol
+		if s.Find("span.icon-gel").Length() > 0 {
+			text = strings.TrimSpace(text) + " ₾"
+		}
+
+		switch tagName {
+		case "p":
+			// For paragraphs, add text followed by two newlines
+			result.WriteString(text)
+			result.WriteString("\n\n")
+		case "ul":
+			// Don't process ul directly, we'll handle li elements
+		case "li":
+			// For list items, add a bullet point
+			result.WriteString("• ")
+			result.WriteString(text)
+			result.WriteString("\n")
+		}
+	})
+
+	// Clean up the result
+	content := result.String()
+
+	// Replace HTML entities
+	content = strings.ReplaceAll(content, "&nbsp;", " ")
+
+	// Clean up any excess newlines
+	for strings.Contains(content, "\n\n\n") {
+		content = strings.ReplaceAll(content, "\n\n\n", "\n\n")
+	}
+
+	return strings.TrimSpace(content)
+}
+
+// formatInlineHTML walks s's children, escaping plain text and re-emitting the small
+// subset of inline tags Telegram's HTML parse mode understands.
+func formatInlineHTML(s *goquery.Selection) string {
+	var b strings.Builder
+
+	s.Contents().Each(func(i int, c *goquery.Selection) {
+		node := c.Get(0)
+		if node == nil {
+			return
+		}
+
+		switch node.Type {
+		case html.TextNode:
+			b.WriteString(escapeTelegramHTML(node.Data))
+		case html.ElementNode:
+			switch node.Data {
+			case "a":
+				href, _ := c.Attr("href")
+				fmt.Fprintf(&b, `<a href="%s">%s</a>`, escapeTelegramHTMLAttr(href), formatInlineHTML(c))
+			case "strong", "b":
+				b.WriteString("<b>" + formatInlineHTML(c) + "</b>")
+			case "em", "i":
+				b.WriteString("<i>" + formatInlineHTML(c) + "</i>")
+			case "br":
+				b.WriteString("\n")
+			default:
+				b.WriteString(formatInlineHTML(c))
+			}
+		}
+	})
+
+	return b.String()
+}
+
+// escapeTelegramHTML escapes the characters Telegram's HTML parse mode requires
+// escaped in text content.
+func escapeTelegramHTML(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	return s
+}
+
+// escapeTelegramHTMLAttr additionally escapes quotes, for use inside attribute values.
+func escapeTelegramHTMLAttr(s string) string {
+	return strings.ReplaceAll(escapeTelegramHTML(s), `"`, "&quot;")
+}
+
+// splitTelegramMessage splits text into chunks that each fit within limit runes,
+// preferring to break on paragraph boundaries so tags never get split across
+// messages there. A single paragraph longer than limit is hard-split as a last
+// resort, on a rune boundary and, where possible, not inside an open HTML tag.
+func splitTelegramMessage(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentLen := 0
+
+	flush := func() {
+		if currentLen > 0 {
+			chunks = append(chunks, strings.TrimSpace(current.String()))
+			current.Reset()
+			currentLen = 0
+		}
+	}
+
+	for _, para := range strings.Split(text, "\n\n") {
+		paraLen := utf8.RuneCountInString(para)
+		if currentLen > 0 && currentLen+paraLen+2 > limit {
+			flush()
+		}
+		if currentLen > 0 {
+			current.WriteString("\n\n")
+			currentLen += 2
+		}
+		current.WriteString(para)
+		currentLen += paraLen
+
+		for currentLen > limit {
+			runes := []rune(current.String())
+			cut := hardSplitPoint(runes, limit)
+			chunks = append(chunks, string(runes[:cut]))
+			current.Reset()
+			current.WriteString(string(runes[cut:]))
+			currentLen = len(runes) - cut
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// hardSplitPoint returns a rune index at or before limit where runes can be safely
+// cut: not in the middle of a "<...>" tag, and not between an opening tag (e.g.
+// "<a href=...>") and its matching close (e.g. "</a>") - cutting there would leave
+// one chunk with an unclosed tag and the next with an orphan close tag, which
+// Telegram's HTML parse mode rejects. It backs up to the start of the outermost
+// tag still open at limit; if that tag itself is longer than limit (pathological),
+// it falls back to cutting at limit regardless.
+func hardSplitPoint(runes []rune, limit int) int {
+	if limit >= len(runes) {
+		return len(runes)
+	}
+
+	var openStarts []int
+	for i := 0; i < limit; i++ {
+		if runes[i] != '<' {
+			continue
+		}
+		start := i
+		end := start
+		for end < len(runes) && runes[end] != '>' {
+			end++
+		}
+		if end >= len(runes) {
+			break // unterminated to the end of the text; let the fallback handle it
+		}
+		if end >= limit {
+			// This tag straddles limit, so it can't be considered closed either
+			// way; cut before it, or before an outer tag still open from earlier.
+			return firstNonZero(openStarts, start, limit)
+		}
+
+		tag := string(runes[start+1 : end])
+		if strings.HasPrefix(tag, "/") {
+			if len(openStarts) > 0 {
+				openStarts = openStarts[:len(openStarts)-1]
+			}
+		} else {
+			openStarts = append(openStarts, start)
+		}
+		i = end
+	}
+
+	return firstNonZero(openStarts, limit, limit)
+}
+
+// firstNonZero returns openStarts[0] if set and non-zero, otherwise fallback; a
+// cut point of 0 would make no progress (the whole text stays open past limit), so
+// that's the pathological case where hardSplitPoint gives up and cuts at fallback
+// instead of respecting tag boundaries.
+func firstNonZero(openStarts []int, ifEmpty, fallback int) int {
+	cut := ifEmpty
+	if len(openStarts) > 0 {
+		cut = openStarts[0]
+	}
+	if cut == 0 {
+		return fallback
+	}
+	return cut
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitTelegramMessageDoesNotSplitInsideTag(t *testing.T) {
+	const limit = 50
+
+	// A link element that fits within limit on its own, but whose position in the
+	// text straddles the naive cut point: without tag-pair tracking, the raw rune
+	// cut lands between <a href="...​"> and its matching </a>.
+	text := strings.Repeat("x", 30) + `<a href="https://x.co">click here</a>` + strings.Repeat("y", 40)
+
+	chunks := splitTelegramMessage(text, limit)
+	if len(chunks) < 2 {
+		t.Fatalf("expected text longer than limit to be split, got %d chunk(s)", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		if utf8.RuneCountInString(chunk) > limit {
+			t.Errorf("chunk %d exceeds limit: %d runes", i, utf8.RuneCountInString(chunk))
+		}
+		if strings.Count(chunk, "<a ") != strings.Count(chunk, "</a>") {
+			t.Errorf("chunk %d has an unbalanced <a> tag: %q", i, chunk)
+		}
+	}
+
+	if strings.Join(chunks, "") != text {
+		t.Fatalf("split chunks, once rejoined, don't reconstruct the original text")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// healthServerShutdownTimeout bounds how long shutdownHealthServer waits for
+// in-flight requests (health checks, scrapes, pprof dumps) to finish.
+const healthServerShutdownTimeout = 5 * time.Second
+
+// startHealthServer starts the daemon-mode HTTP server exposing /healthz, /metrics
+// and /debug/pprof on addr. It listens in the background; Serve errors other than a
+// clean shutdown are logged but don't stop the process, since this endpoint is
+// diagnostic and shouldn't take the fetch/post loop down with it.
+func startHealthServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		metrics.writePrometheusText(w)
+	})
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			a.error("Health server stopped unexpectedly", err, "listen_addr", addr)
+		}
+	}()
+
+	return srv
+}
+
+// shutdownHealthServer gracefully stops srv, giving in-flight requests up to
+// healthServerShutdownTimeout to complete.
+func shutdownHealthServer(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthServerShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		a.error("Error shutting down health server", err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// appLogger wraps slog.Logger with the short helper methods used at call sites
+// throughout this package (a.info/a.warn/a.error/a.fatal).
+type appLogger struct {
+	*slog.Logger
+}
+
+// a is the process-wide logger. It starts with sane defaults so startup errors
+// (which happen before Settings are loaded) are still logged structurally, and is
+// reconfigured by configureLogging once Settings are available.
+var a = newAppLogger(slog.LevelInfo, "text", nil)
+
+func newAppLogger(level slog.Level, format string, w io.Writer) *appLogger {
+	if w == nil {
+		w = os.Stderr
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	return &appLogger{slog.New(handler)}
+}
+
+// configureLogging rebuilds the global logger from settings.log_level/log_format
+// and, when settings.log_file is set, points it at that file so operators running
+// this as a service can ship logs to journald/Loki instead of a bare console.
+func configureLogging(settings *Settings) error {
+	var w io.Writer = os.Stderr
+	if settings.LogFile != "" {
+		f, err := os.OpenFile(settings.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("error opening log file: %w", err)
+		}
+		w = f
+	}
+
+	a = newAppLogger(parseLogLevel(settings.LogLevel), settings.LogFormat, w)
+	return nil
+}
+
+func parseLogLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func (l *appLogger) info(msg string, args ...any) { l.Logger.Info(msg, args...) }
+func (l *appLogger) warn(msg string, args ...any) { l.Logger.Warn(msg, args...) }
+
+// error logs msg at error level with err attached under the "error" key, unless
+// err is nil (some startup failures are reported without a wrapped error value).
+func (l *appLogger) error(msg string, err error, args ...any) {
+	if err != nil {
+		args = append([]any{"error", err}, args...)
+	}
+	l.Logger.Error(msg, args...)
+}
+
+// fatal logs a structured error event and exits non-zero, replacing the old
+// log.Fatalf/log.Panicf startup paths.
+func (l *appLogger) fatal(msg string, err error, args ...any) {
+	l.error(msg, err, args...)
+	os.Exit(1)
+}
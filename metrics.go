@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// appMetrics holds the process-wide counters exposed on /metrics in daemon mode.
+// Counters are plain atomics rather than a client library, matching the rest of the
+// package's preference for small hand-rolled pieces over new dependencies.
+type appMetrics struct {
+	fetchesTotal   *counterByLabel // label: source
+	fetchErrors    *counterByLabel // label: source
+	articlesPosted *counterByLabel // label: publisher
+	articlesEdited *counterByLabel // label: publisher
+	sendErrors     *counterByLabel // label: publisher
+	httpStatus     *counterByLabel // label: status code, as a string
+}
+
+// metrics is the process-wide metrics instance, mirroring how the package-level `a`
+// logger is used throughout.
+var metrics = newAppMetrics()
+
+func newAppMetrics() *appMetrics {
+	return &appMetrics{
+		fetchesTotal:   newCounterByLabel(),
+		fetchErrors:    newCounterByLabel(),
+		articlesPosted: newCounterByLabel(),
+		articlesEdited: newCounterByLabel(),
+		sendErrors:     newCounterByLabel(),
+		httpStatus:     newCounterByLabel(),
+	}
+}
+
+// counterByLabel is a set of independently-incrementable counters keyed by a single
+// label value (source name, publisher name, HTTP status code, ...).
+type counterByLabel struct {
+	mu     sync.Mutex
+	counts map[string]*int64
+}
+
+func newCounterByLabel() *counterByLabel {
+	return &counterByLabel{counts: make(map[string]*int64)}
+}
+
+func (c *counterByLabel) inc(label string) {
+	c.mu.Lock()
+	n, ok := c.counts[label]
+	if !ok {
+		n = new(int64)
+		c.counts[label] = n
+	}
+	c.mu.Unlock()
+	atomic.AddInt64(n, 1)
+}
+
+// snapshot returns the current counts sorted by label, for deterministic /metrics output.
+func (c *counterByLabel) snapshot() []labelCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]labelCount, 0, len(c.counts))
+	for label, n := range c.counts {
+		out = append(out, labelCount{label: label, count: atomic.LoadInt64(n)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].label < out[j].label })
+	return out
+}
+
+type labelCount struct {
+	label string
+	count int64
+}
+
+func (m *appMetrics) fetchAttempted(source string)   { m.fetchesTotal.inc(source) }
+func (m *appMetrics) fetchFailed(source string)      { m.fetchErrors.inc(source) }
+func (m *appMetrics) articlePosted(publisher string) { m.articlesPosted.inc(publisher) }
+func (m *appMetrics) articleEdited(publisher string) { m.articlesEdited.inc(publisher) }
+func (m *appMetrics) sendFailed(publisher string)    { m.sendErrors.inc(publisher) }
+func (m *appMetrics) magticomHTTPStatus(statusCode int) {
+	m.httpStatus.inc(fmt.Sprintf("%d", statusCode))
+}
+
+// writePrometheusText renders all counters in Prometheus text exposition format.
+func (m *appMetrics) writePrometheusText(w http.ResponseWriter) {
+	writeMetricFamily(w, "tgbot_fetches_total", "Number of fetch attempts per source.", "source", m.fetchesTotal)
+	writeMetricFamily(w, "tgbot_fetch_errors_total", "Number of failed fetch attempts per source.", "source", m.fetchErrors)
+	writeMetricFamily(w, "tgbot_articles_posted_total", "Number of articles successfully posted per publisher.", "publisher", m.articlesPosted)
+	writeMetricFamily(w, "tgbot_articles_edited_total", "Number of previously-posted articles edited in place per publisher.", "publisher", m.articlesEdited)
+	writeMetricFamily(w, "tgbot_send_errors_total", "Number of failed publish attempts per publisher.", "publisher", m.sendErrors)
+	writeMetricFamily(w, "tgbot_magticom_http_status_total", "HTTP status codes returned by magticom.ge, by code.", "code", m.httpStatus)
+}
+
+func writeMetricFamily(w http.ResponseWriter, name, help, labelName string, c *counterByLabel) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	for _, lc := range c.snapshot() {
+		fmt.Fprintf(w, "%s{%s=%q} %d\n", name, labelName, lc.label, lc.count)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// telegramTagPattern strips the small Telegram HTML tag set parseHtmlContent
+// produces, since Mastodon statuses are plain text.
+var telegramTagPattern = regexp.MustCompile(`<[^>]+>`)
+
+func stripTelegramHTML(s string) string {
+	return html.UnescapeString(telegramTagPattern.ReplaceAllString(s, ""))
+}
+
+const mastodonDefaultVisibility = "public"
+
+// mastodonPublisher posts Articles as statuses to a Mastodon/Pleroma account via the
+// Mastodon HTTP API, so the same Article stream can mirror to the Fediverse for users
+// who don't use Telegram.
+type mastodonPublisher struct {
+	name        string
+	instanceURL string
+	accessToken string
+	visibility  string
+	client      *http.Client
+}
+
+func newMastodonPublisher(cfg PublisherConfig) *mastodonPublisher {
+	visibility := cfg.Visibility
+	if visibility == "" {
+		visibility = mastodonDefaultVisibility
+	}
+	return &mastodonPublisher{
+		name:        cfg.Name,
+		instanceURL: strings.TrimSuffix(cfg.InstanceURL, "/"),
+		accessToken: cfg.AccessToken,
+		visibility:  visibility,
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *mastodonPublisher) Name() string { return p.name }
+
+// Publish posts article as a new status. It doesn't return a usable message ID:
+// mastodonPublisher doesn't implement EditablePublisher, so Magticom edits to an
+// already-posted article are left as-is here rather than reposted or edited.
+func (p *mastodonPublisher) Publish(ctx context.Context, article Article) (int64, error) {
+	status := article.Title
+	if article.SectionContent != "" {
+		status = fmt.Sprintf("%s\n\n%s", status, stripTelegramHTML(article.SectionContent))
+	}
+	status = fmt.Sprintf("%s\n\n%s", status, article.URL)
+
+	form := url.Values{}
+	form.Set("status", status)
+	form.Set("visibility", p.visibility)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		p.instanceURL+"/api/v1/statuses", strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, fmt.Errorf("error building request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.accessToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error posting status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return 0, fmt.Errorf("mastodon returned status %d", resp.StatusCode)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+const telegramPublisherName = "telegram"
+
+const (
+	telegramSendMaxElapsed = 2 * time.Minute // give up retrying a single send after this long
+	telegramBackoffBase    = 500 * time.Millisecond
+	telegramBackoffMax     = 30 * time.Second
+
+	telegramGlobalRate   = 30.0        // msg/sec across all chats, Telegram's documented Bot API ceiling
+	telegramGlobalBurst  = 30.0
+	telegramChannelRate  = 20.0 / 60.0 // msg/sec for a single channel, i.e. 20/min
+	telegramChannelBurst = 20.0
+)
+
+// telegramGlobalLimiter caps outgoing requests across every telegramPublisher in the
+// process (there's normally just one, but nothing stops settings.json from
+// configuring more than one telegram destination).
+var telegramGlobalLimiter = newTokenBucket(telegramGlobalRate, telegramGlobalBurst)
+
+// telegramPublisher is the bot's original (and default) destination.
+type telegramPublisher struct {
+	botToken  string
+	channelID int64
+
+	mu  sync.Mutex
+	bot *tgbotapi.BotAPI // lazily created and cached across calls, see api()
+
+	channelLimiter *tokenBucket
+}
+
+func newTelegramPublisher(secrets *Secrets, settings *Settings) *telegramPublisher {
+	return &telegramPublisher{
+		botToken:       secrets.TelegramBotToken,
+		channelID:      settings.TelegramChannelID,
+		channelLimiter: newTokenBucket(telegramChannelRate, telegramChannelBurst),
+	}
+}
+
+func (p *telegramPublisher) Name() string { return telegramPublisherName }
+
+func (p *telegramPublisher) Publish(ctx context.Context, article Article) (int64, error) {
+	messageID, err := sendToTelegram(ctx, p, article)
+	return int64(messageID), err
+}
+
+// Edit updates a message previously sent by Publish in place, so a Magticom edit to
+// an already-posted article is reflected without a duplicate post.
+func (p *telegramPublisher) Edit(ctx context.Context, messageID int64, article Article) error {
+	return editTelegramMessage(ctx, p, int(messageID), article)
+}
+
+// api lazily creates and caches the tgbotapi client, so repeated sends don't redo
+// the getMe handshake tgbotapi.NewBotAPI performs on every call.
+func (p *telegramPublisher) api() (*tgbotapi.BotAPI, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.bot != nil {
+		return p.bot, nil
+	}
+
+	bot, err := tgbotapi.NewBotAPI(p.botToken)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing bot: %v", err)
+	}
+	p.bot = bot
+	return bot, nil
+}
+
+// send delivers chattable, rate-limited by the global and per-channel token
+// buckets, retrying on Telegram's 429 (honoring its retry_after) and on transient
+// network errors with exponential backoff and jitter. It gives up once
+// telegramSendMaxElapsed has passed since the first attempt.
+func (p *telegramPublisher) send(ctx context.Context, chattable tgbotapi.Chattable) (tgbotapi.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	bot, err := p.api()
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	deadline := time.Now().Add(telegramSendMaxElapsed)
+	backoff := telegramBackoffBase
+
+	for attempt := 1; ; attempt++ {
+		if err := telegramGlobalLimiter.wait(ctx); err != nil {
+			return tgbotapi.Message{}, err
+		}
+		if err := p.channelLimiter.wait(ctx); err != nil {
+			return tgbotapi.Message{}, err
+		}
+
+		msg, err := bot.Send(chattable)
+		if err == nil {
+			return msg, nil
+		}
+
+		wait, retryable := telegramRetryDelay(err, backoff)
+		if !retryable || time.Now().Add(wait).After(deadline) {
+			return tgbotapi.Message{}, err
+		}
+
+		a.warn("Telegram send failed, retrying", "attempt", attempt, "wait", wait, "error", err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return tgbotapi.Message{}, ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > telegramBackoffMax {
+			backoff = telegramBackoffMax
+		}
+	}
+}
+
+// telegramRetryDelay decides whether err is worth retrying and how long to wait
+// first. A 429's retry_after is authoritative and takes priority over our own
+// backoff. tgbotapi.Error carries no HTTP status, so any other API error (bad
+// token, bad request, chat not found, ...) is treated as permanent; non-API errors
+// (network failures, timeouts) are assumed transient and get backoff with jitter.
+func telegramRetryDelay(err error, backoff time.Duration) (time.Duration, bool) {
+	if tgErr, ok := err.(tgbotapi.Error); ok {
+		if tgErr.RetryAfter > 0 {
+			return time.Duration(tgErr.RetryAfter) * time.Second, true
+		}
+		return 0, false
+	}
+
+	return jitter(backoff), true
+}
+
+// jitter spreads retries out over roughly [d/2, 1.5d) so a burst of failures
+// doesn't retry in lockstep.
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
+
+// sendToTelegram posts article via p and returns the ID of the sent message, so it
+// can be targeted by editTelegramMessage if Magticom edits the article later. The ID
+// is only returned when the article fit in a single message (a photo caption or one
+// text chunk); when splitTelegramMessage had to split it across several messages, 0
+// is returned and a later content change is posted as a new message rather than
+// edited.
+func sendToTelegram(ctx context.Context, p *telegramPublisher, article Article) (int, error) {
+	message := formatTelegramMessage(article)
+	channelTarget := fmt.Sprintf("%d", p.channelID)
+
+	if article.ImageURL != "" && len(message) <= telegramCaptionLimit {
+		// NewPhotoShare takes a file_id, but the Bot API also accepts a plain URL
+		// string there; there's no NewPhoto/FileURL in this (v4) package, those are
+		// v5-only.
+		photo := tgbotapi.NewPhotoShare(0, article.ImageURL)
+		photo.ChannelUsername = channelTarget
+		photo.Caption = message
+		photo.ParseMode = tgbotapi.ModeHTML
+
+		sent, err := p.send(ctx, photo)
+		if err != nil {
+			return 0, fmt.Errorf("error sending photo: %v", err)
+		}
+		return sent.MessageID, nil
+	}
+
+	chunks := splitTelegramMessage(message, telegramMessageLimit)
+	var firstMessageID int
+	for i, chunk := range chunks {
+		msg := tgbotapi.NewMessageToChannel(channelTarget, chunk)
+		msg.ParseMode = tgbotapi.ModeHTML
+		msg.DisableWebPagePreview = false
+
+		sent, err := p.send(ctx, msg)
+		if err != nil {
+			return 0, fmt.Errorf("error sending message: %v", err)
+		}
+		if i == 0 {
+			firstMessageID = sent.MessageID
+		}
+	}
+	if len(chunks) > 1 {
+		return 0, nil
+	}
+	return firstMessageID, nil
+}
+
+// editTelegramMessage updates a message previously sent by sendToTelegram in place.
+// Like sendToTelegram, it only handles content that fits a single message; an edit
+// that would now need a photo/caption switch or no longer fits one message is
+// reported as an error and the stale copy is left posted, rather than risk mangling
+// an unrelated message.
+func editTelegramMessage(ctx context.Context, p *telegramPublisher, messageID int, article Article) error {
+	message := formatTelegramMessage(article)
+	channelTarget := fmt.Sprintf("%d", p.channelID)
+
+	if article.ImageURL != "" && len(message) <= telegramCaptionLimit {
+		edit := tgbotapi.NewEditMessageCaption(0, messageID, message)
+		edit.ChannelUsername = channelTarget
+		edit.ParseMode = tgbotapi.ModeHTML
+
+		if _, err := p.send(ctx, edit); err != nil {
+			return fmt.Errorf("error editing caption: %v", err)
+		}
+		return nil
+	}
+
+	if len(message) > telegramMessageLimit {
+		return fmt.Errorf("updated article no longer fits a single message")
+	}
+
+	edit := tgbotapi.NewEditMessageText(0, messageID, message)
+	edit.ChannelUsername = channelTarget
+	edit.ParseMode = tgbotapi.ModeHTML
+
+	if _, err := p.send(ctx, edit); err != nil {
+		return fmt.Errorf("error editing message: %v", err)
+	}
+	return nil
+}
+
+// formatTelegramMessage renders article the way it appears in Telegram: date, the
+// Telegram-safe HTML produced by parseHtmlContent, then a link back to the source.
+func formatTelegramMessage(article Article) string {
+	dateText := escapeTelegramHTML(strings.TrimSpace(article.Text))
+	return fmt.Sprintf("📅 %s\n\n%s\n\n🔗 %s", dateText, article.SectionContent, article.URL)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestTelegramRetryDelay(t *testing.T) {
+	const backoff = time.Second
+
+	t.Run("429 honors retry_after", func(t *testing.T) {
+		err := tgbotapi.Error{
+			Message:            "Too Many Requests",
+			ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 5},
+		}
+		wait, retryable := telegramRetryDelay(err, backoff)
+		if !retryable {
+			t.Fatal("expected a 429 to be retryable")
+		}
+		if wait != 5*time.Second {
+			t.Fatalf("wait = %v, want 5s", wait)
+		}
+	})
+
+	t.Run("400 is not retried", func(t *testing.T) {
+		err := tgbotapi.Error{Message: "Bad Request: chat not found"}
+		_, retryable := telegramRetryDelay(err, backoff)
+		if retryable {
+			t.Fatal("expected a plain API error to not be retried")
+		}
+	})
+
+	t.Run("network error gets backoff with jitter", func(t *testing.T) {
+		wait, retryable := telegramRetryDelay(errors.New("connection reset"), backoff)
+		if !retryable {
+			t.Fatal("expected a non-API error to be retryable")
+		}
+		if wait < backoff/2 || wait >= 3*backoff/2 {
+			t.Fatalf("wait = %v, want roughly [%v, %v)", wait, backoff/2, 3*backoff/2)
+		}
+	})
+}
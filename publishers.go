@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Publisher delivers an Article to one destination (Telegram, Mastodon/ActivityPub,
+// ...). Multiple Publishers can be enabled at once; the scheduler posts to each
+// independently and tracks success per (publisher, source, url), so a failure in one
+// publisher doesn't block the others and only the failed destination is retried.
+type Publisher interface {
+	// Name identifies the publisher for logging and is part of the dedupe key in the
+	// SQLite store, so it must stay stable across runs.
+	Name() string
+	// Publish sends article and returns the ID of the resulting message, if the
+	// destination exposes one and the message was sent as a single unit (0
+	// otherwise). The ID is persisted so a later EditablePublisher.Edit can target it.
+	Publish(ctx context.Context, article Article) (messageID int64, err error)
+}
+
+// EditablePublisher is implemented by Publishers that can update a message already
+// sent, so the scheduler can react to Magticom silently editing an article after
+// publication by editing the existing post instead of reposting or ignoring the
+// change. Publishers that don't implement it simply keep their original post.
+type EditablePublisher interface {
+	Publisher
+	Edit(ctx context.Context, messageID int64, article Article) error
+}
+
+// PublisherConfig describes one configured Publisher in settings.json. Type selects
+// which concrete Publisher gets built; the remaining fields are interpreted
+// according to Type.
+type PublisherConfig struct {
+	Type        string `json:"type"` // "telegram" or "mastodon"
+	Name        string `json:"name"`
+	InstanceURL string `json:"instance_url,omitempty"` // mastodon only
+	AccessToken string `json:"access_token,omitempty"` // mastodon only
+	Visibility  string `json:"visibility,omitempty"`   // mastodon only; defaults to "public"
+}
+
+// buildPublishers turns the configured PublisherConfigs into concrete Publishers. If
+// none are configured it falls back to a single Telegram publisher, matching the
+// tool's original single-destination behavior.
+func buildPublishers(secrets *Secrets, settings *Settings) ([]Publisher, error) {
+	if len(settings.Publishers) == 0 {
+		return []Publisher{newTelegramPublisher(secrets, settings)}, nil
+	}
+
+	publishers := make([]Publisher, 0, len(settings.Publishers))
+	for _, cfg := range settings.Publishers {
+		switch cfg.Type {
+		case "telegram":
+			publishers = append(publishers, newTelegramPublisher(secrets, settings))
+		case "mastodon":
+			if cfg.InstanceURL == "" || cfg.AccessToken == "" {
+				return nil, fmt.Errorf("publisher %q: mastodon requires instance_url and access_token", cfg.Name)
+			}
+			publishers = append(publishers, newMastodonPublisher(cfg))
+		default:
+			return nil, fmt.Errorf("publisher %q: unknown type %q", cfg.Name, cfg.Type)
+		}
+	}
+	return publishers, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a blocking token-bucket rate limiter: it holds at most capacity
+// tokens, refilling continuously at rate tokens/sec. Used to keep Telegram sends
+// within the Bot API's rate limits instead of discovering them via 429s.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // tokens added per second
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate, capacity float64) *tokenBucket {
+	return &tokenBucket{rate: rate, capacity: capacity, tokens: capacity, last: time.Now()}
+}
+
+// wait blocks until a token is available (refilling the bucket as time passes) or
+// ctx is done, whichever comes first.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		d := b.reserve()
+		if d <= 0 {
+			return nil
+		}
+
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve refills the bucket for elapsed time and, if a token is available, takes
+// it and returns 0. Otherwise it returns how long the caller should wait before
+// trying again.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
@@ -1,19 +1,20 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"log"
-	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
-	"time"
+	"syscall"
 
-	"github.com/PuerkitoBio/goquery"
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
 
@@ -24,16 +25,23 @@ type Secrets struct {
 
 // Settings holds the configuration settings for the bot
 type Settings struct {
-	TelegramChannelID int64 `json:"telegram_channel_id"`
+	TelegramChannelID int64             `json:"telegram_channel_id"`
+	Sources           []SourceConfig    `json:"sources,omitempty"`    // defaults to a single Magticom source when empty
+	Publishers        []PublisherConfig `json:"publishers,omitempty"` // defaults to a single Telegram publisher when empty
+	LogLevel          string            `json:"log_level,omitempty"`  // debug|info|warn|error, defaults to info
+	LogFormat         string            `json:"log_format,omitempty"` // text|json, defaults to text
+	LogFile           string            `json:"log_file,omitempty"`   // defaults to stderr when empty
 }
 
 // Article represents a news item
 type Article struct {
+	Source         string // name of the Source that produced this Article
 	Title          string
 	URL            string
 	Date           string
 	Text           string
-	SectionContent string
+	SectionContent string // Telegram-safe HTML, see parseHtmlContent
+	ImageURL       string // hero image, if any; sent via sendPhoto when it fits in the caption limit
 }
 
 const (
@@ -46,13 +54,13 @@ func getLocalAppDataDir() string {
 	if runtime.GOOS == "windows" {
 		localAppData := os.Getenv("LOCALAPPDATA")
 		if localAppData == "" {
-			log.Panicf("LOCALAPPDATA environment variable is not set")
+			a.fatal("LOCALAPPDATA environment variable is not set", nil)
 		}
 		return localAppData
 	} else {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			log.Panicf("Error getting home directory: %v", err)
+			a.fatal("Error getting home directory", err)
 		}
 		return filepath.Join(homeDir, ".local")
 	}
@@ -81,7 +89,7 @@ func getDBPath() string {
 
 	// Ensure directory exists
 	if err := os.MkdirAll(dataDir, 0700); err != nil {
-		log.Fatalf("Error creating data directory: %v", err)
+		a.fatal("Error creating data directory", err)
 	}
 
 	return filepath.Join(dataDir, dbFileName)
@@ -93,40 +101,235 @@ func initDB(dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("error opening database: %w", err)
 	}
 
-	// Create table with URL and timestamp
+	if err := migrateLegacySchema(db); err != nil {
+		return nil, err
+	}
+
+	// Create table keyed by (publisher, source, url) so each publisher tracks its own
+	// delivery of a given article independently: a failure in one publisher doesn't
+	// stop others from posting, and retries only re-target the failed destination.
 	query := `
     CREATE TABLE IF NOT EXISTS posted_articles (
-        url TEXT PRIMARY KEY,
-        posted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+        publisher TEXT NOT NULL DEFAULT 'telegram',
+        source TEXT NOT NULL DEFAULT 'magticom',
+        url TEXT NOT NULL,
+        content_hash TEXT,
+        telegram_message_id INTEGER,
+        posted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (publisher, source, url)
     );
     `
-	_, err = db.Exec(query)
-	if err != nil {
+	if _, err := db.Exec(query); err != nil {
 		return nil, fmt.Errorf("error creating table: %w", err)
 	}
 
+	// Older databases predate content_hash/telegram_message_id; add them as nullable
+	// columns so existing rows are left with no hash and get backfilled the next time
+	// their article is seen, instead of being re-posted.
+	if err := addColumnIfMissing(db, "posted_articles", "content_hash", "TEXT"); err != nil {
+		return nil, err
+	}
+	if err := addColumnIfMissing(db, "posted_articles", "telegram_message_id", "INTEGER"); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
-func isArticlePosted(db *sql.DB, url string) (bool, error) {
-	query := "SELECT 1 FROM posted_articles WHERE url = ?"
-	var exists int
-	err := db.QueryRow(query, url).Scan(&exists)
+// migrateLegacySchema rebuilds posted_articles if it predates the (publisher,
+// source, url) composite primary key - i.e. it's a database from before the bot
+// tracked multiple sources/publishers, keyed on url alone. ADD COLUMN can't change
+// a table's primary key, so unlike addColumnIfMissing this has to rename the old
+// table aside, recreate it with the current schema, and copy the rows across,
+// backfilling publisher/source with the single values every row implicitly had
+// before this distinction existed.
+func migrateLegacySchema(db *sql.DB) error {
+	exists, err := tableExists(db, "posted_articles")
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return false, nil
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	cols, err := tableColumns(db, "posted_articles")
+	if err != nil {
+		return err
+	}
+	if cols["publisher"] && cols["source"] {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting schema migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`ALTER TABLE posted_articles RENAME TO posted_articles_old`); err != nil {
+		return fmt.Errorf("error renaming legacy posted_articles: %w", err)
+	}
+
+	create := `
+    CREATE TABLE posted_articles (
+        publisher TEXT NOT NULL DEFAULT 'telegram',
+        source TEXT NOT NULL DEFAULT 'magticom',
+        url TEXT NOT NULL,
+        content_hash TEXT,
+        telegram_message_id INTEGER,
+        posted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (publisher, source, url)
+    );
+    `
+	if _, err := tx.Exec(create); err != nil {
+		return fmt.Errorf("error recreating posted_articles: %w", err)
+	}
+
+	selectExpr := func(column, fallback string) string {
+		if cols[column] {
+			return column
 		}
-		return false, fmt.Errorf("error checking if article posted: %w", err)
+		return fallback
+	}
+	copyQuery := fmt.Sprintf(`
+    INSERT INTO posted_articles (publisher, source, url, content_hash, telegram_message_id, posted_at)
+    SELECT %s, %s, url, %s, %s, %s FROM posted_articles_old
+    `,
+		selectExpr("publisher", "'telegram'"),
+		selectExpr("source", "'magticom'"),
+		selectExpr("content_hash", "NULL"),
+		selectExpr("telegram_message_id", "NULL"),
+		selectExpr("posted_at", "CURRENT_TIMESTAMP"),
+	)
+	if _, err := tx.Exec(copyQuery); err != nil {
+		return fmt.Errorf("error copying rows into migrated posted_articles: %w", err)
+	}
+
+	if _, err := tx.Exec(`DROP TABLE posted_articles_old`); err != nil {
+		return fmt.Errorf("error dropping legacy posted_articles: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing schema migration: %w", err)
+	}
+
+	a.info("Migrated posted_articles to composite (publisher, source, url) primary key")
+	return nil
+}
+
+// tableExists reports whether table is present in the database's schema.
+func tableExists(db *sql.DB, table string) (bool, error) {
+	var name string
+	err := db.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&name)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking for table %s: %w", table, err)
 	}
 	return true, nil
 }
 
-func markArticleAsPosted(db *sql.DB, url string) error {
-	query := "INSERT INTO posted_articles (url) VALUES (?)"
-	_, err := db.Exec(query, url)
+// tableColumns returns the set of column names table currently has.
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, fmt.Errorf("error reading table info for %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return nil, fmt.Errorf("error scanning table info for %s: %w", table, err)
+		}
+		cols[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading table info for %s: %w", table, err)
+	}
+	return cols, nil
+}
+
+// addColumnIfMissing adds column to table with the given SQLite type affinity
+// unless it's already there, so initDB can migrate databases created before a
+// column existed without disturbing the rows already in them.
+func addColumnIfMissing(db *sql.DB, table, column, sqlType string) error {
+	cols, err := tableColumns(db, table)
+	if err != nil {
+		return err
+	}
+	if cols[column] {
+		return nil
+	}
+
+	alter := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, sqlType)
+	if _, err := db.Exec(alter); err != nil {
+		return fmt.Errorf("error adding column %s to %s: %w", column, table, err)
+	}
+	return nil
+}
+
+// postedArticleState is what's known about a previously-posted (publisher, source,
+// url): the content hash it was posted with, and the message ID to target if a
+// later edit needs to update it in place.
+type postedArticleState struct {
+	ContentHash       string
+	TelegramMessageID int64
+}
+
+// getPostedArticleState looks up the posting state for (publisher, source, url). It
+// returns (nil, nil) if the article hasn't been posted by this publisher before.
+func getPostedArticleState(db *sql.DB, publisher, source, url string) (*postedArticleState, error) {
+	query := "SELECT content_hash, telegram_message_id FROM posted_articles WHERE publisher = ? AND source = ? AND url = ?"
+	var hash sql.NullString
+	var messageID sql.NullInt64
+	err := db.QueryRow(query, publisher, source, url).Scan(&hash, &messageID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error checking posted article state: %w", err)
+	}
+	return &postedArticleState{ContentHash: hash.String, TelegramMessageID: messageID.Int64}, nil
+}
+
+// upsertPostedArticle records (publisher, source, url) as posted with contentHash,
+// updating posted_at and the stored message ID if the row already existed - which
+// happens when an edit is recorded, or when backfilling an older row that predates
+// content_hash.
+func upsertPostedArticle(db *sql.DB, publisher, source, url, contentHash string, telegramMessageID int64) error {
+	query := `
+    INSERT INTO posted_articles (publisher, source, url, content_hash, telegram_message_id)
+    VALUES (?, ?, ?, ?, ?)
+    ON CONFLICT (publisher, source, url) DO UPDATE SET
+        content_hash = excluded.content_hash,
+        telegram_message_id = excluded.telegram_message_id,
+        posted_at = CURRENT_TIMESTAMP
+    `
+	var messageID sql.NullInt64
+	if telegramMessageID != 0 {
+		messageID = sql.NullInt64{Int64: telegramMessageID, Valid: true}
+	}
+	_, err := db.Exec(query, publisher, source, url, contentHash, messageID)
 	return err
 }
 
+// contentHash returns a stable digest of an article's normalized SectionContent,
+// used to detect Magticom silently editing an article after it was posted.
+func contentHash(sectionContent string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(sectionContent)))
+	return hex.EncodeToString(sum[:])
+}
+
 func removeOldArticles(db *sql.DB) error {
 	// Delete articles older than one year
 	query := `DELETE FROM posted_articles WHERE posted_at < datetime('now', '-1 year')`
@@ -142,7 +345,7 @@ func removeOldArticles(db *sql.DB) error {
 	}
 
 	if rowsAffected > 0 {
-		log.Printf("Removed %d old articles from database", rowsAffected)
+		a.info("Removed old articles from database", "count", rowsAffected)
 	}
 
 	return nil
@@ -158,14 +361,14 @@ func getSettingsPath() string {
 	} else {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			log.Fatalf("Error getting home directory: %v", err)
+			a.fatal("Error getting home directory", err)
 		}
 		dataDir = filepath.Join(homeDir, ".local", "repost_magti_news")
 	}
 
 	// Ensure directory exists
 	if err := os.MkdirAll(dataDir, 0700); err != nil {
-		log.Fatalf("Error creating data directory: %v", err)
+		a.fatal("Error creating data directory", err)
 	}
 
 	return filepath.Join(dataDir, "settings.json")
@@ -174,12 +377,12 @@ func getSettingsPath() string {
 func loadFile(filePath string, displayType string) []byte {
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		log.Panicf(`%s file not found at "%s"`, displayType, filePath)
+		a.fatal(fmt.Sprintf("%s file not found", displayType), nil, "path", filePath)
 	}
 
 	rawdata, err := os.ReadFile(filePath)
 	if err != nil {
-		log.Panicf(`error %v reading %s file "%s"`, err, displayType, filePath)
+		a.fatal(fmt.Sprintf("error reading %s file", displayType), err, "path", filePath)
 	}
 
 	return rawdata
@@ -213,181 +416,6 @@ func loadSettings() (*Settings, error) {
 	return &settings, nil
 }
 
-func sendToTelegram(botToken string, channelID int64, article Article) error {
-	bot, err := tgbotapi.NewBotAPI(botToken)
-	if err != nil {
-		return fmt.Errorf("error initializing bot: %v", err)
-	}
-
-	// Format the date nicely
-	dateText := strings.TrimSpace(article.Text)
-
-	// The section content is already formatted by parseHtmlContent
-	content := article.SectionContent
-
-	// Format message with proper spacing
-	message := fmt.Sprintf("📅 %s\n\n%s\n\n🔗 %s",
-		dateText, content, article.URL)
-
-	// Use plain text mode
-	msg := tgbotapi.NewMessageToChannel(fmt.Sprintf("%d", channelID), message)
-
-	_, err = bot.Send(msg)
-	if err != nil {
-		return fmt.Errorf("error sending message: %v", err)
-	}
-
-	return nil
-}
-
-func fetchWonderDaysNews() ([]Article, error) {
-	client := &http.Client{
-		Timeout: 120 * time.Second,
-	}
-
-	// Get the news listing page
-	resp, err := client.Get(magticomNewsURL)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching news page: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
-	}
-
-	// Load the HTML document
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing HTML: %v", err)
-	}
-
-	var wonderDaysItems []Article
-
-	// Find news items in the specified selector
-	doc.Find(".post-listing a.post-list-item").Each(func(i int, s *goquery.Selection) {
-		url, exists := s.Attr("href")
-		if exists {
-			// Make relative URLs absolute
-			if !strings.HasPrefix(url, "http") {
-				url = "https://www.magticom.ge/" + strings.TrimPrefix(url, "/")
-			}
-
-			// Extract date from the post listing
-			dateStr := s.Find(".post-date").Text()
-			titleStr := s.Find(".post-content").Text()
-
-			article := Article{
-				Title: strings.TrimSpace(titleStr),
-				URL:   url,
-				Date:  dateStr,
-			}
-
-			// Fetch the full article content
-			content, sectionContent, err := fetchArticleContent(client, url)
-			if err != nil {
-				log.Printf("Warning: couldn't fetch article content: %v", err)
-			} else {
-				article.Text = content
-				article.SectionContent = sectionContent
-			}
-
-			wonderDaysItems = append(wonderDaysItems, article)
-		}
-	})
-
-	return wonderDaysItems, nil
-}
-
-// parseHtmlContent extracts text from HTML content with proper formatting
-func parseHtmlContent(htmlContent string) string {
-	reader := strings.NewReader(htmlContent)
-	doc, err := goquery.NewDocumentFromReader(reader)
-	if err != nil {
-		// If parsing fails, return the original content
-		return htmlContent
-	}
-
-	var result strings.Builder
-
-	// Process paragraphs and lists
-	doc.Find("p, ul, li").Each(func(i int, s *goquery.Selection) {
-		// Get the tag name
-		tagName := goquery.NodeName(s)
-
-		// Clean up the text
-		text := strings.TrimSpace(s.Text())
-		if text == "" {
-			return
-		}
-
-		// Replace Georgian Lari icon with the proper symbol
-		if s.Find("span.icon-gel").Length() > 0 {
-			text = strings.TrimSpace(text) + " ₾"
-		}
-
-		switch tagName {
-		case "p":
-			// For paragraphs, add text followed by two newlines
-			result.WriteString(text)
-			result.WriteString("\n\n")
-		case "ul":
-			// Don't process ul directly, we'll handle li elements
-		case "li":
-			// For list items, add a bullet point
-			result.WriteString("• ")
-			result.WriteString(text)
-			result.WriteString("\n")
-		}
-	})
-
-	// Clean up the result
-	content := result.String()
-
-	// Replace HTML entities
-	content = strings.ReplaceAll(content, "&nbsp;", " ")
-
-	// Clean up any excess newlines
-	for strings.Contains(content, "\n\n\n") {
-		content = strings.ReplaceAll(content, "\n\n\n", "\n\n")
-	}
-
-	return strings.TrimSpace(content)
-}
-
-func fetchArticleContent(client *http.Client, url string) (string, string, error) {
-	resp, err := client.Get(url)
-	if err != nil {
-		return "", "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("bad status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return "", "", err
-	}
-
-	// Extract the date content
-	dateContent := doc.Find("#article > article > div > div").Text()
-
-	// Extract the section content as HTML
-	sectionHtml, err := doc.Find("#article > article > div > section").Html()
-	if err != nil {
-		// If we can't get the HTML, fall back to text
-		sectionContent := doc.Find("#article > article > div > section").Text()
-		return strings.TrimSpace(dateContent), strings.TrimSpace(sectionContent), nil
-	}
-
-	// Parse the HTML content
-	sectionContent := parseHtmlContent(sectionHtml)
-
-	return strings.TrimSpace(dateContent), sectionContent, nil
-}
-
 func printInstructions() {
 	fmt.Println("Missing required configuration for the Magticom News Reposter.")
 	fmt.Println("\nPlease create the following configuration files:")
@@ -413,83 +441,83 @@ func printInstructions() {
 	fmt.Println("   Use the 'Id' number from the 'Forwarded from chat' value (including the negative sign)")
 }
 
-// Run executes the service
-func Run() {
+// Run executes the service. In one-shot mode (daemon == false) it polls every
+// source once and exits, for use under an external cron. In daemon mode it polls
+// continuously on each source's own interval and serves /healthz, /metrics and
+// /debug/pprof on listenAddr until SIGINT/SIGTERM.
+func Run(daemon bool, listenAddr string) {
 	secrets, err := loadSecrets()
 	if err != nil {
-		log.Printf("Error loading secrets: %v", err)
+		a.error("Error loading secrets", err)
 		printInstructions()
 		return
 	}
 
 	settings, err := loadSettings()
 	if err != nil {
-		log.Printf("Error loading settings: %v", err)
+		a.error("Error loading settings", err)
 		printInstructions()
 		return
 	}
 
+	if err := configureLogging(settings); err != nil {
+		a.error("Error configuring logging, falling back to defaults", err)
+	}
+
 	dbPath := getDBPath()
 	db, err := initDB(dbPath)
 	if err != nil {
-		log.Fatalf("Error initializing database: %v", err)
+		a.fatal("Error initializing database", err)
 	}
 	defer db.Close()
 
 	if err := removeOldArticles(db); err != nil {
-		log.Printf("Warning: Error removing old articles: %v", err)
+		a.warn("Error removing old articles", "error", err)
 	}
 
-	log.Println("Fetching news from Magticom...")
-	articles, err := fetchWonderDaysNews()
+	srcs, err := buildSources(settings)
 	if err != nil {
-		log.Fatalf("Error fetching news: %v", err)
+		a.fatal("Error configuring sources", err)
 	}
 
-	if len(articles) == 0 {
-		log.Println("No news found on the page.")
-		return
+	publishers, err := buildPublishers(secrets, settings)
+	if err != nil {
+		a.fatal("Error configuring publishers", err)
 	}
 
-	var newItemsToPost []Article
-	// News items are fetched newest first. We iterate to find new ones.
-	for _, item := range articles {
-		posted, err := isArticlePosted(db, item.URL)
-		if err != nil {
-			log.Printf("Error checking if article was posted (%s): %v. Skipping.", item.URL, err)
-			continue
-		}
-		if !posted {
-			newItemsToPost = append(newItemsToPost, item)
-		}
+	srcNames := make([]string, len(srcs))
+	for i, src := range srcs {
+		srcNames[i] = src.Name()
+	}
+	pubNames := make([]string, len(publishers))
+	for i, pub := range publishers {
+		pubNames[i] = pub.Name()
 	}
+	a.info("Polling sources", "count", len(srcs), "names", strings.Join(srcNames, ", "))
+	a.info("Publishing to", "count", len(publishers), "names", strings.Join(pubNames, ", "))
 
-	if len(newItemsToPost) > 0 {
-		log.Printf("Found %d new items to post.", len(newItemsToPost))
+	if !daemon {
+		runOnce(context.Background(), db, publishers, srcs)
+		return
+	}
 
-		// Post items from oldest to newest (reverse the slice of new items)
-		for i := len(newItemsToPost) - 1; i >= 0; i-- {
-			item := newItemsToPost[i]
-			log.Printf("Posting to Telegram: %s (%s)", item.Title, item.URL)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-			if err := sendToTelegram(secrets.TelegramBotToken, settings.TelegramChannelID, item); err != nil {
-				log.Printf("Error sending to Telegram (%s): %v", item.URL, err)
-				// If sending fails, we don't mark it as posted, so it will be retried next time.
-				continue
-			}
+	healthServer := startHealthServer(listenAddr)
+	defer shutdownHealthServer(healthServer)
 
-			log.Printf("Successfully posted: %s", item.URL)
-			if err := markArticleAsPosted(db, item.URL); err != nil {
-				log.Printf(
-					"Error marking article %s as posted: %v.",
-					item.URL, err)
-			}
-		}
-	} else {
-		log.Println("No new news to post.")
-	}
+	a.info("Running in daemon mode", "listen_addr", listenAddr)
+	runScheduler(ctx, db, publishers, srcs)
+	a.info("Shutdown complete")
 }
 
 func main() {
-	Run()
+	daemon := flag.Bool("daemon", false,
+		"run continuously, polling each source on its own interval, until SIGINT/SIGTERM")
+	listenAddr := flag.String("listen-addr", ":8080",
+		"address for the health/metrics/pprof HTTP server in -daemon mode")
+	flag.Parse()
+
+	Run(*daemon, *listenAddr)
 }
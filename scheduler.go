@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// runScheduler polls every source on its own Interval and posts anything new to each
+// configured Publisher, deduping via db keyed by (publisher, source, url). It blocks
+// until ctx is done.
+func runScheduler(ctx context.Context, db *sql.DB, publishers []Publisher, srcs []Source) {
+	results := make(chan []Article)
+
+	for _, src := range srcs {
+		go pollSource(ctx, src, results)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case articles := <-results:
+			postNewArticles(ctx, db, publishers, articles)
+		}
+	}
+}
+
+// runOnce fetches every source once and posts anything new, then returns. It's used
+// in one-shot mode (no -daemon flag), for use under an external cron, and shares its
+// dedupe/posting logic with runScheduler via postNewArticles.
+func runOnce(ctx context.Context, db *sql.DB, publishers []Publisher, srcs []Source) {
+	var wg sync.WaitGroup
+	for _, src := range srcs {
+		wg.Add(1)
+		go func(src Source) {
+			defer wg.Done()
+			if articles, ok := fetchSource(ctx, src); ok {
+				postNewArticles(ctx, db, publishers, articles)
+			}
+		}(src)
+	}
+	wg.Wait()
+}
+
+// fetchSource calls src.Fetch, recording the attempt in metrics and logging any
+// error. ok is false when the fetch failed or returned nothing to post.
+func fetchSource(ctx context.Context, src Source) (articles []Article, ok bool) {
+	metrics.fetchAttempted(src.Name())
+	articles, err := src.Fetch(ctx)
+	if err != nil {
+		metrics.fetchFailed(src.Name())
+		a.error("Error fetching from source", err, "source", src.Name())
+		return nil, false
+	}
+	return articles, len(articles) > 0
+}
+
+// pollSource fetches src immediately, then again every src.Interval(), sending each
+// non-empty result to results until ctx is done.
+func pollSource(ctx context.Context, src Source, results chan<- []Article) {
+	fetch := func() {
+		articles, ok := fetchSource(ctx, src)
+		if ok {
+			select {
+			case results <- articles:
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	fetch()
+
+	ticker := time.NewTicker(src.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fetch()
+		}
+	}
+}
+
+// postNewArticles checks every article against each publisher's posting state,
+// oldest first (articles arrive newest first from the source): articles never seen
+// before are published, and articles whose content_hash changed since they were
+// posted are edited in place instead, to handle Magticom silently editing a news
+// item after publication. Unchanged articles are left alone.
+func postNewArticles(ctx context.Context, db *sql.DB, publishers []Publisher, articles []Article) {
+	for i := len(articles) - 1; i >= 0; i-- {
+		item := articles[i]
+		hash := contentHash(item.SectionContent)
+
+		for _, pub := range publishers {
+			state, err := getPostedArticleState(db, pub.Name(), item.Source, item.URL)
+			if err != nil {
+				a.error("Error checking posted article state, skipping", err, "publisher", pub.Name(), "url", item.URL)
+				continue
+			}
+
+			switch {
+			case state == nil:
+				publishArticle(ctx, db, pub, item, hash)
+			case state.ContentHash != "" && state.ContentHash != hash:
+				updatePostedArticle(ctx, db, pub, item, hash, *state)
+			case state.ContentHash == "":
+				// Row predates content_hash; backfill it without reposting or editing.
+				if err := upsertPostedArticle(db, pub.Name(), item.Source, item.URL, hash, state.TelegramMessageID); err != nil {
+					a.error("Error backfilling content hash", err, "publisher", pub.Name(), "url", item.URL)
+				}
+			}
+		}
+	}
+}
+
+// publishArticle sends item to pub for the first time and records its posting state.
+func publishArticle(ctx context.Context, db *sql.DB, pub Publisher, item Article, hash string) {
+	a.info("Publishing article", "publisher", pub.Name(), "title", item.Title, "url", item.URL)
+	messageID, err := pub.Publish(ctx, item)
+	if err != nil {
+		metrics.sendFailed(pub.Name())
+		a.error("Error publishing article", err, "publisher", pub.Name(), "url", item.URL)
+		// If publishing fails, we don't record it as posted, so it will be retried next time.
+		return
+	}
+
+	metrics.articlePosted(pub.Name())
+	a.info("Successfully published", "publisher", pub.Name(), "url", item.URL)
+	if err := upsertPostedArticle(db, pub.Name(), item.Source, item.URL, hash, messageID); err != nil {
+		a.error("Error recording posted article", err, "publisher", pub.Name(), "url", item.URL)
+	}
+}
+
+// updatePostedArticle edits item's previously-sent message in place when pub
+// supports it, and records the new content hash. Publishers that don't implement
+// EditablePublisher (or that didn't get a trackable message ID for this article)
+// leave the stale copy posted; the hash is left unchanged so the edit is retried
+// once the publisher can apply it.
+func updatePostedArticle(ctx context.Context, db *sql.DB, pub Publisher, item Article, hash string, state postedArticleState) {
+	editable, ok := pub.(EditablePublisher)
+	if !ok || state.TelegramMessageID == 0 {
+		a.info("Article content changed but publisher can't edit it in place, leaving original post",
+			"publisher", pub.Name(), "url", item.URL)
+		return
+	}
+
+	a.info("Article content changed, editing previous post", "publisher", pub.Name(), "url", item.URL)
+	if err := editable.Edit(ctx, state.TelegramMessageID, item); err != nil {
+		metrics.sendFailed(pub.Name())
+		a.error("Error editing article", err, "publisher", pub.Name(), "url", item.URL)
+		return
+	}
+
+	metrics.articleEdited(pub.Name())
+	if err := upsertPostedArticle(db, pub.Name(), item.Source, item.URL, hash, state.TelegramMessageID); err != nil {
+		a.error("Error recording edited article", err, "publisher", pub.Name(), "url", item.URL)
+	}
+}
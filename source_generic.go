@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const genericDefaultPollIntervalSecs = 1800
+
+// CSSSelectors configures a genericSource. Item scopes each match; the rest are
+// resolved relative to it. Selectors follow goquery (cascadia) syntax.
+type CSSSelectors struct {
+	Item    string `json:"item"`
+	Title   string `json:"title"`
+	Link    string `json:"link"`
+	LinkAttr string `json:"link_attr"` // defaults to "href"
+	Date    string `json:"date"`
+	Content string `json:"content"`
+}
+
+// genericSource scrapes a listing page using CSS selectors supplied in settings.json,
+// for sites that don't warrant a dedicated implementation.
+type genericSource struct {
+	name      string
+	url       string
+	interval  time.Duration
+	selectors CSSSelectors
+	client    *http.Client
+}
+
+func newGenericSource(cfg SourceConfig) *genericSource {
+	return &genericSource{
+		name:      cfg.Name,
+		url:       cfg.URL,
+		interval:  cfg.interval(genericDefaultPollIntervalSecs),
+		selectors: *cfg.Selectors,
+		client:    &http.Client{Timeout: magticomHTTPTimeout},
+	}
+}
+
+func (s *genericSource) Name() string           { return s.name }
+func (s *genericSource) Interval() time.Duration { return s.interval }
+
+func (s *genericSource) Fetch(ctx context.Context) ([]Article, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %v", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %v", err)
+	}
+
+	linkAttr := s.selectors.LinkAttr
+	if linkAttr == "" {
+		linkAttr = "href"
+	}
+
+	var articles []Article
+	doc.Find(s.selectors.Item).Each(func(i int, sel *goquery.Selection) {
+		url, exists := sel.Find(s.selectors.Link).Attr(linkAttr)
+		if !exists || url == "" {
+			return
+		}
+		if !strings.HasPrefix(url, "http") {
+			url = strings.TrimSuffix(s.url, "/") + "/" + strings.TrimPrefix(url, "/")
+		}
+
+		content := sel.Find(s.selectors.Content).Text()
+
+		articles = append(articles, Article{
+			Source:         s.name,
+			Title:          strings.TrimSpace(sel.Find(s.selectors.Title).Text()),
+			URL:            url,
+			Date:           strings.TrimSpace(sel.Find(s.selectors.Date).Text()),
+			Text:           strings.TrimSpace(content),
+			SectionContent: strings.TrimSpace(content),
+		})
+	})
+
+	return articles, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	magticomSourceName   = "magticom"
+	magticomPollInterval = time.Hour
+	magticomHTTPTimeout  = 120 * time.Second
+)
+
+// magticomSource scrapes the Magticom news listing page, the bot's original (and
+// only) source before the Source abstraction was introduced.
+type magticomSource struct {
+	client *http.Client
+}
+
+func newMagticomSource() *magticomSource {
+	return &magticomSource{client: &http.Client{Timeout: magticomHTTPTimeout}}
+}
+
+func (s *magticomSource) Name() string           { return magticomSourceName }
+func (s *magticomSource) Interval() time.Duration { return magticomPollInterval }
+
+func (s *magticomSource) Fetch(ctx context.Context) ([]Article, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, magticomNewsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %v", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching news page: %v", err)
+	}
+	defer resp.Body.Close()
+	metrics.magticomHTTPStatus(resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %v", err)
+	}
+
+	var articles []Article
+
+	doc.Find(".post-listing a.post-list-item").Each(func(i int, sel *goquery.Selection) {
+		url, exists := sel.Attr("href")
+		if !exists {
+			return
+		}
+		// Make relative URLs absolute
+		if !strings.HasPrefix(url, "http") {
+			url = "https://www.magticom.ge/" + strings.TrimPrefix(url, "/")
+		}
+
+		dateStr := sel.Find(".post-date").Text()
+		titleStr := sel.Find(".post-content").Text()
+
+		article := Article{
+			Source: magticomSourceName,
+			Title:  strings.TrimSpace(titleStr),
+			URL:    url,
+			Date:   dateStr,
+		}
+
+		content, sectionContent, imageURL, err := fetchArticleContent(ctx, s.client, url)
+		if err != nil {
+			a.warn("Couldn't fetch article content", "url", url, "error", err)
+		} else {
+			article.Text = content
+			article.SectionContent = sectionContent
+			article.ImageURL = imageURL
+		}
+
+		articles = append(articles, article)
+	})
+
+	return articles, nil
+}
+
+func fetchArticleContent(ctx context.Context, client *http.Client, url string) (string, string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", "", fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// Extract the date content
+	dateContent := doc.Find("#article > article > div > div").Text()
+
+	section := doc.Find("#article > article > div > section")
+	imageURL, _ := section.Find("img").First().Attr("src")
+	if imageURL != "" && !strings.HasPrefix(imageURL, "http") {
+		imageURL = "https://www.magticom.ge/" + strings.TrimPrefix(imageURL, "/")
+	}
+
+	// Extract the section content as HTML
+	sectionHtml, err := section.Html()
+	if err != nil {
+		// If we can't get the HTML, fall back to text
+		sectionContent := section.Text()
+		return strings.TrimSpace(dateContent), strings.TrimSpace(sectionContent), imageURL, nil
+	}
+
+	// Parse the HTML content into Telegram-safe HTML
+	sectionContent := parseHtmlContent(sectionHtml)
+
+	return strings.TrimSpace(dateContent), sectionContent, imageURL, nil
+}
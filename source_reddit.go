@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const redditDefaultPollIntervalSecs = 900
+
+// redditListing is the subset of Reddit's `/r/<sub>/new.json` response shape needed
+// to turn posts into Articles.
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data struct {
+				Title     string  `json:"title"`
+				Permalink string  `json:"permalink"`
+				URL       string  `json:"url"`
+				SelfText  string  `json:"selftext"`
+				Created   float64 `json:"created_utc"`
+			} `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// redditSource polls a subreddit's JSON listing for new posts. cfg.URL holds the
+// subreddit name (e.g. "golang"), not a full URL.
+type redditSource struct {
+	name      string
+	subreddit string
+	interval  time.Duration
+	client    *http.Client
+}
+
+func newRedditSource(cfg SourceConfig) *redditSource {
+	return &redditSource{
+		name:      cfg.Name,
+		subreddit: strings.TrimPrefix(cfg.URL, "r/"),
+		interval:  cfg.interval(redditDefaultPollIntervalSecs),
+		client:    &http.Client{Timeout: magticomHTTPTimeout},
+	}
+}
+
+func (s *redditSource) Name() string           { return s.name }
+func (s *redditSource) Interval() time.Duration { return s.interval }
+
+func (s *redditSource) Fetch(ctx context.Context) ([]Article, error) {
+	listingURL := fmt.Sprintf("https://www.reddit.com/r/%s/new.json?limit=25", s.subreddit)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listingURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %v", err)
+	}
+	// Reddit rejects the default Go User-Agent.
+	req.Header.Set("User-Agent", "tgbot:repost-reddit-source:v1")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching listing: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	var listing redditListing
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("error parsing listing: %v", err)
+	}
+
+	articles := make([]Article, 0, len(listing.Data.Children))
+	for _, child := range listing.Data.Children {
+		post := child.Data
+		articles = append(articles, Article{
+			Source:         s.name,
+			Title:          strings.TrimSpace(post.Title),
+			URL:            "https://www.reddit.com" + post.Permalink,
+			Date:           strconv.FormatFloat(post.Created, 'f', 0, 64),
+			Text:           strings.TrimSpace(post.SelfText),
+			SectionContent: strings.TrimSpace(post.SelfText),
+		})
+	}
+
+	return articles, nil
+}
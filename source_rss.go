@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const rssDefaultPollIntervalSecs = 1800
+
+// rssFeed is just enough of the RSS 2.0 / Atom schemas to extract a flat list of
+// entries; the two formats share field names closely enough to decode with one struct.
+type rssFeed struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Entries []rssItem `xml:"entry"` // Atom
+}
+
+type rssItem struct {
+	Title       string    `xml:"title"`
+	Link        []rssLink `xml:"link"`
+	Description string    `xml:"description"`
+	Summary     string    `xml:"summary"` // Atom
+	PubDate     string    `xml:"pubDate"`
+	Updated     string    `xml:"updated"` // Atom
+}
+
+// rssLink covers both RSS's <link>https://example.com/a</link> (plain chardata) and
+// Atom's <link rel="alternate" href="https://example.com/a"/> (attributes, no
+// chardata, and possibly more than one per entry) under a single xml:"link" tag.
+type rssLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+	Data string `xml:",chardata"`
+}
+
+// url returns the single article URL for item, preferring RSS's plain-text link,
+// then an Atom "alternate" (or untyped) link, then falling back to the first link
+// seen at all.
+func (item rssItem) url() string {
+	var first string
+	for _, l := range item.Link {
+		if l.Data != "" {
+			return strings.TrimSpace(l.Data)
+		}
+		if l.Href == "" {
+			continue
+		}
+		if first == "" {
+			first = l.Href
+		}
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
+	}
+	return first
+}
+
+// rssSource polls a single RSS or Atom feed URL.
+type rssSource struct {
+	name     string
+	url      string
+	interval time.Duration
+	client   *http.Client
+}
+
+func newRSSSource(cfg SourceConfig) *rssSource {
+	return &rssSource{
+		name:     cfg.Name,
+		url:      cfg.URL,
+		interval: cfg.interval(rssDefaultPollIntervalSecs),
+		client:   &http.Client{Timeout: magticomHTTPTimeout},
+	}
+}
+
+func (s *rssSource) Name() string           { return s.name }
+func (s *rssSource) Interval() time.Duration { return s.interval }
+
+func (s *rssSource) Fetch(ctx context.Context) ([]Article, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %v", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching feed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	var feed rssFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("error parsing feed: %v", err)
+	}
+
+	items := feed.Channel.Items
+	if len(items) == 0 {
+		items = feed.Entries
+	}
+
+	articles := make([]Article, 0, len(items))
+	for _, item := range items {
+		link := item.url()
+		if link == "" {
+			continue
+		}
+
+		text := item.Description
+		if text == "" {
+			text = item.Summary
+		}
+		date := item.PubDate
+		if date == "" {
+			date = item.Updated
+		}
+
+		articles = append(articles, Article{
+			Source:         s.name,
+			Title:          strings.TrimSpace(item.Title),
+			URL:            link,
+			Date:           date,
+			Text:           strings.TrimSpace(text),
+			SectionContent: parseHtmlContent(text),
+		})
+	}
+
+	return articles, nil
+}
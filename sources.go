@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Source is implemented by anything that can produce a stream of news Articles.
+// Concrete sources (Magticom, RSS/Atom feeds, Reddit, generic CSS-selector scraping)
+// are built from Settings in buildSources and polled independently by runScheduler,
+// each on its own Interval.
+type Source interface {
+	// Name identifies the source for logging and is part of the dedupe key in the
+	// SQLite store, so it must stay stable across runs.
+	Name() string
+	// Fetch retrieves the news items currently available from the source, newest first.
+	Fetch(ctx context.Context) ([]Article, error)
+	// Interval is how often the scheduler should poll this source.
+	Interval() time.Duration
+}
+
+// SourceConfig describes one configured Source in settings.json. Type selects which
+// concrete Source gets built; the remaining fields are interpreted according to Type.
+type SourceConfig struct {
+	Type             string        `json:"type"` // "magticom", "rss", "reddit" or "generic"
+	Name             string        `json:"name"`
+	URL              string        `json:"url"`
+	PollIntervalSecs int           `json:"poll_interval_secs"`
+	Selectors        *CSSSelectors `json:"selectors,omitempty"` // only used by "generic"
+}
+
+func (c SourceConfig) interval(defaultSecs int) time.Duration {
+	secs := c.PollIntervalSecs
+	if secs <= 0 {
+		secs = defaultSecs
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// buildSources turns the configured SourceConfigs into concrete Sources. If none are
+// configured it falls back to a single Magticom source, matching the tool's original
+// single-purpose behavior.
+func buildSources(settings *Settings) ([]Source, error) {
+	if len(settings.Sources) == 0 {
+		return []Source{newMagticomSource()}, nil
+	}
+
+	sources := make([]Source, 0, len(settings.Sources))
+	for _, cfg := range settings.Sources {
+		switch cfg.Type {
+		case "magticom":
+			sources = append(sources, newMagticomSource())
+		case "rss":
+			sources = append(sources, newRSSSource(cfg))
+		case "reddit":
+			sources = append(sources, newRedditSource(cfg))
+		case "generic":
+			if cfg.Selectors == nil {
+				return nil, fmt.Errorf("source %q: generic sources require \"selectors\"", cfg.Name)
+			}
+			sources = append(sources, newGenericSource(cfg))
+		default:
+			return nil, fmt.Errorf("source %q: unknown type %q", cfg.Name, cfg.Type)
+		}
+	}
+	return sources, nil
+}